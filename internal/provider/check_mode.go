@@ -0,0 +1,194 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.ResourceWithModifyPlan = &RunResource{}
+
+// checkModeCallback is the minimal shape read from ansible-playbook's json callback output when
+// run with --check --diff, used by ModifyPlan to detect plan-time drift.
+type checkModeCallback struct {
+	Plays []struct {
+		Tasks []struct {
+			Task struct {
+				Name string `json:"name"`
+			} `json:"task"`
+			Hosts map[string]struct {
+				Changed bool            `json:"changed"`
+				Diff    json.RawMessage `json:"diff"`
+			} `json:"hosts"`
+		} `json:"tasks"`
+	} `json:"plays"`
+	Stats map[string]struct {
+		Changed int64 `json:"changed"`
+	} `json:"stats"`
+}
+
+// ansibleDiffEntry mirrors the subset of Ansible's per-task `diff` document used to render a
+// human-readable diff: either a ready-made unified diff in `prepared`, or a before/after pair.
+type ansibleDiffEntry struct {
+	BeforeHeader string      `json:"before_header"`
+	AfterHeader  string      `json:"after_header"`
+	Before       interface{} `json:"before"`
+	After        interface{} `json:"after"`
+	Prepared     string      `json:"prepared"`
+}
+
+// parseDiffEntries normalizes a task's `diff` field, which Ansible emits as either a single object
+// or (for modules that loop over items) a list of objects, into a flat slice of entries.
+func parseDiffEntries(raw json.RawMessage) ([]ansibleDiffEntry, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || string(trimmed) == "null" {
+		return nil, nil
+	}
+
+	if trimmed[0] == '[' {
+		var entries []ansibleDiffEntry
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, err
+		}
+		return entries, nil
+	}
+
+	var entry ansibleDiffEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, err
+	}
+	return []ansibleDiffEntry{entry}, nil
+}
+
+// renderDiffEntry renders a single diff entry as `ansible-playbook --diff` would print it: the
+// module's own prepared unified diff if it supplied one, otherwise a before/after rendering.
+func renderDiffEntry(e ansibleDiffEntry) string {
+	if e.Prepared != "" {
+		return e.Prepared
+	}
+
+	beforeHeader := e.BeforeHeader
+	if beforeHeader == "" {
+		beforeHeader = "before"
+	}
+	afterHeader := e.AfterHeader
+	if afterHeader == "" {
+		afterHeader = "after"
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", beforeHeader, afterHeader)
+	fmt.Fprintf(&out, "-%s\n+%s\n", renderDiffSide(e.Before), renderDiffSide(e.After))
+	return out.String()
+}
+
+// renderDiffSide renders one side of a before/after diff pair as text: as-is if it's already a
+// string (e.g. file content), or JSON-encoded otherwise (e.g. a module's structured attributes).
+func renderDiffSide(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	enc, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(enc)
+}
+
+// parseCheckMode parses the json-callback output of a `--check --diff` run into the total number
+// of tasks that would change and a human-readable rendering of their diffs.
+func parseCheckMode(out []byte) (int64, string, error) {
+	var doc checkModeCallback
+	if err := json.Unmarshal(out, &doc); err != nil {
+		return 0, "", fmt.Errorf("unable to parse ansible-playbook check-mode output: %w", err)
+	}
+
+	var pending int64
+	for _, stats := range doc.Stats {
+		pending += stats.Changed
+	}
+
+	var diff strings.Builder
+	for _, play := range doc.Plays {
+		for _, task := range play.Tasks {
+			for host, hr := range task.Hosts {
+				if !hr.Changed || len(hr.Diff) == 0 || string(hr.Diff) == "null" {
+					continue
+				}
+				entries, err := parseDiffEntries(hr.Diff)
+				if err != nil {
+					return 0, "", fmt.Errorf("unable to parse diff for host %q task %q: %w", host, task.Task.Name, err)
+				}
+				fmt.Fprintf(&diff, "%s / %s:\n", host, task.Task.Name)
+				for _, entry := range entries {
+					diff.WriteString(renderDiffEntry(entry))
+				}
+				diff.WriteString("\n")
+			}
+		}
+	}
+
+	return pending, diff.String(), nil
+}
+
+// ModifyPlan runs ansible-playbook in check mode during "terraform plan" and, when it reports drift
+// on an otherwise-unchanged resource, marks pending_changes/diff unknown so that an update is
+// planned. The changed count and diff it computes are not written to the plan.
+func (r *RunResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		// Destroy or create: there is no existing resource to check for drift against.
+		return
+	}
+	if !r.providerModel.CheckOnPlan.ValueBool() {
+		return
+	}
+
+	var plan RunResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.CheckMode.ValueBool() {
+		return
+	}
+
+	out, err := r.runPlaybook(ctx, &plan, "--check", "--diff", "-D")
+	if err != nil {
+		resp.Diagnostics.AddError("Error", err.Error())
+		return
+	}
+
+	pending, _, err := parseCheckMode(out)
+	if err != nil {
+		resp.Diagnostics.AddError("Error", err.Error())
+		return
+	}
+
+	if pending == 0 {
+		// No drift: leave pending_changes/diff untouched so they keep their prior state value and
+		// this plan doesn't show a change.
+		return
+	}
+
+	// Mark pending_changes/diff unknown, rather than writing the computed values directly, so
+	// Terraform schedules the update without an apply-time consistency constraint on their value.
+	// execute writes the real (reconciled) values once the update actually runs; if it instead set
+	// them to the just-computed drift values, the very next plan would see them differ from 0/""
+	// and schedule a second, unnecessary update.
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("pending_changes"), types.Int64Unknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("diff"), types.StringUnknown())...)
+}
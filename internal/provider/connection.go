@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// ConnectionModel mirrors the standard Terraform provisioner `connection` block, plus the
+// Ansible-specific privilege escalation fields.
+type ConnectionModel struct {
+	User           types.String `tfsdk:"user"`
+	Password       types.String `tfsdk:"password"`
+	PrivateKey     types.String `tfsdk:"private_key"`
+	PrivateKeyFile types.String `tfsdk:"private_key_file"`
+	HostKey        types.String `tfsdk:"host_key"`
+	Port           types.Int64  `tfsdk:"port"`
+	Agent          types.Bool   `tfsdk:"agent"`
+	Become         types.Bool   `tfsdk:"become"`
+	BecomeUser     types.String `tfsdk:"become_user"`
+	BecomeMethod   types.String `tfsdk:"become_method"`
+	BecomePassword types.String `tfsdk:"become_password"`
+}
+
+func connectionSchemaBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Connection details mirroring a Terraform provisioner `connection` block, translated " +
+			"into ansible-playbook connection and privilege escalation flags. Replaces the need to encode connection " +
+			"details as a JSON suffix on each entry of `hosts`.",
+		Attributes: map[string]schema.Attribute{
+			"user": schema.StringAttribute{
+				MarkdownDescription: "The user to connect as, passed as `--user`.",
+				Optional:            true,
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The connection password, passed as `ansible_password`.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"private_key": schema.StringAttribute{
+				MarkdownDescription: "Inline SSH private key material, materialized to a mode-0600 temporary file and passed as `--private-key`.",
+				Optional:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("private_key_file")),
+				},
+			},
+			"private_key_file": schema.StringAttribute{
+				MarkdownDescription: "Path to an SSH private key file, passed as `--private-key`.",
+				Optional:            true,
+			},
+			"host_key": schema.StringAttribute{
+				MarkdownDescription: "The expected host SSH key, passed as `ansible_ssh_host_key`.",
+				Optional:            true,
+			},
+			"port": schema.Int64Attribute{
+				MarkdownDescription: "The connection port, passed as `ansible_port`.",
+				Optional:            true,
+			},
+			"agent": schema.BoolAttribute{
+				MarkdownDescription: "Whether to use an SSH agent, passed as `ansible_ssh_use_agent`.",
+				Optional:            true,
+			},
+			"become": schema.BoolAttribute{
+				MarkdownDescription: "Whether to escalate privileges on the target host, passed as `--become`.",
+				Optional:            true,
+			},
+			"become_user": schema.StringAttribute{
+				MarkdownDescription: "The user to become, passed as `--become-user`.",
+				Optional:            true,
+			},
+			"become_method": schema.StringAttribute{
+				MarkdownDescription: "The privilege escalation method to use, passed as `--become-method`.",
+				Optional:            true,
+			},
+			"become_password": schema.StringAttribute{
+				MarkdownDescription: "The become password. Materialized to a mode-0600 temporary file referenced " +
+					"via the `ANSIBLE_BECOME_PASSWORD_FILE` environment variable, in place of `--ask-become-pass`.",
+				Optional:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+// connectionArgs resolves the resource's `connection` block (if any) into ansible-playbook CLI
+// flags and child process environment variables, along with a cleanup func that removes any
+// temporary file it created.
+func connectionArgs(ctx context.Context, connection types.Object) ([]string, []string, func(), error) {
+	noop := func() {}
+	if connection.IsNull() || connection.IsUnknown() {
+		return nil, nil, noop, nil
+	}
+
+	var conn ConnectionModel
+	if diags := connection.As(ctx, &conn, basetypes.ObjectAsOptions{}); diags.HasError() {
+		return nil, nil, noop, fmt.Errorf("unable to read connection configuration: %s", diags[0].Detail())
+	}
+
+	var args []string
+	var env []string
+	cleanup := noop
+
+	if !conn.User.IsNull() && conn.User.ValueString() != "" {
+		args = append(args, "--user", conn.User.ValueString())
+	}
+	if !conn.Password.IsNull() && conn.Password.ValueString() != "" {
+		args = append(args, "-e", "ansible_password="+conn.Password.ValueString())
+	}
+
+	switch {
+	case !conn.PrivateKey.IsNull() && conn.PrivateKey.ValueString() != "":
+		f, err := writeTempFile("private-key-*", []byte(conn.PrivateKey.ValueString()), 0o600)
+		if err != nil {
+			return nil, nil, noop, err
+		}
+		args = append(args, "--private-key", f)
+		cleanup = func() { _ = os.Remove(f) }
+	case !conn.PrivateKeyFile.IsNull() && conn.PrivateKeyFile.ValueString() != "":
+		args = append(args, "--private-key", conn.PrivateKeyFile.ValueString())
+	}
+
+	if !conn.HostKey.IsNull() && conn.HostKey.ValueString() != "" {
+		args = append(args, "-e", "ansible_ssh_host_key="+conn.HostKey.ValueString())
+	}
+	if !conn.Port.IsNull() && !conn.Port.IsUnknown() {
+		args = append(args, "-e", fmt.Sprintf("ansible_port=%d", conn.Port.ValueInt64()))
+	}
+	if !conn.Agent.IsNull() && !conn.Agent.IsUnknown() {
+		args = append(args, "-e", fmt.Sprintf("ansible_ssh_use_agent=%t", conn.Agent.ValueBool()))
+	}
+	if conn.Become.ValueBool() {
+		args = append(args, "--become")
+	}
+	if !conn.BecomeUser.IsNull() && conn.BecomeUser.ValueString() != "" {
+		args = append(args, "--become-user", conn.BecomeUser.ValueString())
+	}
+	if !conn.BecomeMethod.IsNull() && conn.BecomeMethod.ValueString() != "" {
+		args = append(args, "--become-method", conn.BecomeMethod.ValueString())
+	}
+	if !conn.BecomePassword.IsNull() && conn.BecomePassword.ValueString() != "" {
+		f, err := writeTempFile("become-password-*", []byte(conn.BecomePassword.ValueString()), 0o600)
+		if err != nil {
+			return args, nil, cleanup, err
+		}
+		previous := cleanup
+		cleanup = func() { previous(); _ = os.Remove(f) }
+		env = append(env, "ANSIBLE_BECOME_PASSWORD_FILE="+f)
+	}
+
+	return args, env, cleanup, nil
+}
@@ -0,0 +1,221 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"gopkg.in/yaml.v3"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ ephemeral.EphemeralResource = &RunEphemeralResource{}
+
+func NewRunEphemeralResource() ephemeral.EphemeralResource {
+	return &RunEphemeralResource{}
+}
+
+// RunEphemeralResource executes a playbook once per `Open` call with no persisted state and no
+// CRUD lifecycle, for playbooks whose outputs (e.g. bootstrap tokens) should never land in state.
+type RunEphemeralResource struct {
+	providerModel AnsiblePlayProviderModel
+}
+
+type RunEphemeralResourceModel struct {
+	Hosts        types.List   `tfsdk:"hosts"`
+	Inventory    types.Map    `tfsdk:"inventory"`
+	PlaybookFile types.String `tfsdk:"playbook_file"`
+	ExtraVars    types.String `tfsdk:"extra_vars_json"`
+	Stdout       types.String `tfsdk:"stdout"`
+	Stderr       types.String `tfsdk:"stderr"`
+	Recap        types.Map    `tfsdk:"recap"`
+}
+
+type hostRecapModel struct {
+	Ok          types.Int64 `tfsdk:"ok"`
+	Changed     types.Int64 `tfsdk:"changed"`
+	Unreachable types.Int64 `tfsdk:"unreachable"`
+	Failed      types.Int64 `tfsdk:"failed"`
+	Skipped     types.Int64 `tfsdk:"skipped"`
+	Rescued     types.Int64 `tfsdk:"rescued"`
+	Ignored     types.Int64 `tfsdk:"ignored"`
+}
+
+var hostRecapAttrTypes = map[string]attr.Type{
+	"ok":          types.Int64Type,
+	"changed":     types.Int64Type,
+	"unreachable": types.Int64Type,
+	"failed":      types.Int64Type,
+	"skipped":     types.Int64Type,
+	"rescued":     types.Int64Type,
+	"ignored":     types.Int64Type,
+}
+
+func (r *RunEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_run"
+}
+
+func (r *RunEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `The run ephemeral resource executes the given playbook_file during "terraform plan" and
+"terraform apply" and exposes its output only for the duration of that plan/apply, never persisting a run to state.
+Use this instead of ansibleplay_run when the playbook should always re-run, or when it produces values (such as a
+bootstrap token) that must not be written to state.
+`,
+		Attributes: map[string]schema.Attribute{
+			"hosts": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "A list of hosts to run the playbook on. Each host (an ip or hostname) may be followed by a space and a JSON object of host attributes. Merged into the implicit `all` group alongside any groups declared in `inventory`.",
+				Optional:            true,
+			},
+			"inventory": inventorySchemaAttribute(),
+			"playbook_file": schema.StringAttribute{
+				MarkdownDescription: "A path to the playbook file to run.",
+				Required:            true,
+			},
+			"extra_vars_json": schema.StringAttribute{
+				MarkdownDescription: "A json-encoded map of extra variables to pass to the playbook.",
+				Optional:            true,
+			},
+			"stdout": schema.StringAttribute{
+				MarkdownDescription: "The human-readable stdout produced by ansible-playbook.",
+				Computed:            true,
+			},
+			"stderr": schema.StringAttribute{
+				MarkdownDescription: "The stderr produced by ansible-playbook.",
+				Computed:            true,
+			},
+			"recap": schema.MapNestedAttribute{
+				MarkdownDescription: "The play recap, keyed by hostname.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"ok":          schema.Int64Attribute{Computed: true},
+						"changed":     schema.Int64Attribute{Computed: true},
+						"unreachable": schema.Int64Attribute{Computed: true},
+						"failed":      schema.Int64Attribute{Computed: true},
+						"skipped":     schema.Int64Attribute{Computed: true},
+						"rescued":     schema.Int64Attribute{Computed: true},
+						"ignored":     schema.Int64Attribute{Computed: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *RunEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	var ok bool
+	if r.providerModel, ok = req.ProviderData.(AnsiblePlayProviderModel); !ok {
+		resp.Diagnostics.AddError("failed to convert provider data to AnsiblePlayProviderModel", "provider data is not AnsiblePlayProviderModel")
+		return
+	}
+}
+
+func (r *RunEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data RunEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	stdout, stderr, err := r.execute(ctx, data)
+	if err != nil {
+		resp.Diagnostics.AddError("Error", err.Error())
+		return
+	}
+
+	data.Stdout = types.StringValue(stdout)
+	data.Stderr = types.StringValue(stderr)
+
+	recap, diags := types.MapValueFrom(ctx, types.ObjectType{AttrTypes: hostRecapAttrTypes}, parsePlayRecap(stdout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Recap = recap
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+func (r *RunEphemeralResource) execute(ctx context.Context, data RunEphemeralResourceModel) (string, string, error) {
+	inventory, err := buildInventory(ctx, data.Hosts, data.Inventory)
+	if err != nil {
+		return "", "", err
+	}
+
+	tf, err := os.CreateTemp(os.TempDir(), "inventory-*.yml")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create temporary inventory file: %w", err)
+	}
+	if err := yaml.NewEncoder(tf).Encode(inventory); err != nil {
+		return "", "", fmt.Errorf("failed to write temporary inventory file: %w", err)
+	}
+	if err := tf.Close(); err != nil {
+		return "", "", fmt.Errorf("failed to close temporary inventory file: %w", err)
+	}
+
+	args := []string{
+		data.PlaybookFile.ValueString(), "-i", tf.Name(),
+	}
+	if !data.ExtraVars.IsNull() {
+		args = append(args, "--extra-vars", data.ExtraVars.ValueString())
+	}
+	args = append(args, verbosityArgs(r.providerModel)...)
+
+	c := exec.CommandContext(ctx, resolveBinary(r.providerModel), args...)
+	outBuffer := &bytes.Buffer{}
+	errBuffer := &bytes.Buffer{}
+	c.Stdout = outBuffer
+	c.Stderr = errBuffer
+	err = c.Run()
+
+	tflog.Info(ctx, "ansible play output: "+outBuffer.String())
+
+	if err != nil {
+		return outBuffer.String(), errBuffer.String(), fmt.Errorf("ansible play failed: %w: %s", err, errBuffer.String())
+	}
+
+	return outBuffer.String(), errBuffer.String(), nil
+}
+
+var playRecapLineRe = regexp.MustCompile(`(?m)^(\S+)\s*:\s*ok=(\d+)\s+changed=(\d+)\s+unreachable=(\d+)\s+failed=(\d+)\s+skipped=(\d+)\s+rescued=(\d+)\s+ignored=(\d+)`)
+
+// parsePlayRecap extracts the per-host counts from the human-readable "PLAY RECAP" section of
+// ansible-playbook's stdout.
+func parsePlayRecap(stdout string) map[string]hostRecapModel {
+	recap := map[string]hostRecapModel{}
+	for _, match := range playRecapLineRe.FindAllStringSubmatch(stdout, -1) {
+		recap[match[1]] = hostRecapModel{
+			Ok:          types.Int64Value(mustAtoi64(match[2])),
+			Changed:     types.Int64Value(mustAtoi64(match[3])),
+			Unreachable: types.Int64Value(mustAtoi64(match[4])),
+			Failed:      types.Int64Value(mustAtoi64(match[5])),
+			Skipped:     types.Int64Value(mustAtoi64(match[6])),
+			Rescued:     types.Int64Value(mustAtoi64(match[7])),
+			Ignored:     types.Int64Value(mustAtoi64(match[8])),
+		}
+	}
+	return recap
+}
+
+func mustAtoi64(s string) int64 {
+	v, _ := strconv.ParseInt(s, 10, 64)
+	return v
+}
@@ -0,0 +1,22 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import "strings"
+
+// resolveBinary returns the configured ansible-playbook binary, defaulting to looking it up on PATH.
+func resolveBinary(providerModel AnsiblePlayProviderModel) string {
+	if binary := providerModel.AnsiblePlaybookBinary.ValueString(); binary != "" {
+		return binary
+	}
+	return "ansible-playbook"
+}
+
+// verbosityArgs returns the `-v`-style flag for the provider's configured verbosity, if any.
+func verbosityArgs(providerModel AnsiblePlayProviderModel) []string {
+	if v := providerModel.Verbosity.ValueInt32(); v > 0 {
+		return []string{"-" + strings.Repeat("v", int(v))}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// InventoryGroupModel describes a single named group within the structured `inventory` attribute.
+type InventoryGroupModel struct {
+	Hosts    types.Map    `tfsdk:"hosts"`
+	Vars     types.String `tfsdk:"vars"`
+	Children types.List   `tfsdk:"children"`
+}
+
+func inventorySchemaAttribute() schema.MapNestedAttribute {
+	return schema.MapNestedAttribute{
+		MarkdownDescription: "A structured inventory keyed by group name, modeling Ansible's YAML inventory format. " +
+			"Groups may nest other groups via `children`; the result is merged under `all.children` when the playbook " +
+			"runs. The flat `hosts` attribute, if set, is merged into an implicit `all` group alongside any groups " +
+			"defined here.",
+		Optional: true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"hosts": schema.MapAttribute{
+					ElementType:         types.StringType,
+					MarkdownDescription: "A map of hostname to a JSON-encoded object of host variables for this group.",
+					Optional:            true,
+				},
+				"vars": schema.StringAttribute{
+					MarkdownDescription: "A JSON-encoded map of group variables (Ansible's `group_vars`) applied to every host in this group.",
+					Optional:            true,
+				},
+				"children": schema.ListAttribute{
+					ElementType:         types.StringType,
+					MarkdownDescription: "The names of other groups declared in `inventory` to nest beneath this group.",
+					Optional:            true,
+				},
+			},
+		},
+	}
+}
+
+// parseFlatHosts turns the flat `hosts` attribute into the map of hostname to host variables
+// expected by the Ansible YAML inventory format, as used for the implicit `all` group.
+func parseFlatHosts(hostsList types.List) (map[string]interface{}, error) {
+	hosts := make(map[string]interface{})
+	for _, value := range hostsList.Elements() {
+		hv, _ := value.(basetypes.StringValue)
+		hostAndJsonAttr := strings.SplitN(hv.ValueString(), " ", 2)
+		attr := map[string]interface{}{}
+		if len(hostAndJsonAttr) == 2 {
+			if err := json.Unmarshal([]byte(hostAndJsonAttr[1]), &attr); err != nil {
+				return nil, fmt.Errorf("unable to parse host attributes for '%s': %w", hostAndJsonAttr[0], err)
+			}
+		}
+		hosts[hostAndJsonAttr[0]] = attr
+	}
+	return hosts, nil
+}
+
+// buildInventory resolves the flat `hosts` list and the structured `inventory` attribute into the
+// `all` group that is YAML-encoded into the temporary inventory file passed to ansible-playbook.
+func buildInventory(ctx context.Context, hosts types.List, inventory types.Map) (map[string]interface{}, error) {
+	flatHosts, err := parseFlatHosts(hosts)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := map[string]InventoryGroupModel{}
+	if !inventory.IsNull() && !inventory.IsUnknown() {
+		for name, value := range inventory.Elements() {
+			obj, ok := value.(basetypes.ObjectValue)
+			if !ok {
+				continue
+			}
+			var group InventoryGroupModel
+			if d := obj.As(ctx, &group, basetypes.ObjectAsOptions{}); d.HasError() {
+				return nil, fmt.Errorf("unable to read inventory group %q: %s", name, d.Errors()[0].Detail())
+			}
+			groups[name] = group
+		}
+	}
+
+	// A group is only nested under `all.children` directly if nothing else already claims it as
+	// a child; otherwise it is reachable through its parent's own `children` entry.
+	nested := map[string]bool{}
+	for _, group := range groups {
+		for _, child := range group.Children.Elements() {
+			if cv, ok := child.(basetypes.StringValue); ok {
+				nested[cv.ValueString()] = true
+			}
+		}
+	}
+
+	all := map[string]interface{}{
+		"hosts": flatHosts,
+	}
+
+	// visited accumulates every group reached from a root, across the whole walk (unlike seen,
+	// which groupToYAML clears as each call returns); it is used below to catch a rootless cycle,
+	// where every group claims to be some other group's child and so none is ever visited.
+	visited := map[string]bool{}
+	children := map[string]interface{}{}
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if nested[name] {
+			continue
+		}
+		group, err := groupToYAML(name, groups, map[string]bool{}, visited)
+		if err != nil {
+			return nil, err
+		}
+		children[name] = group
+	}
+	if len(children) > 0 {
+		all["children"] = children
+	}
+
+	for _, name := range names {
+		if !visited[name] {
+			return nil, fmt.Errorf("inventory group %q is unreachable from \"all\": it and its ancestors form a cycle with no root", name)
+		}
+	}
+
+	return map[string]interface{}{"all": all}, nil
+}
+
+// groupToYAML recursively resolves a named inventory group (and its children) into the nested
+// map structure Ansible expects under `children` in its YAML inventory format. seen tracks the
+// current ancestor path, not every group visited, so a group reachable as a legitimate child of
+// two different ancestors (a diamond) is not mistaken for a cycle. visited records every group
+// reached across the whole walk, so buildInventory can detect a rootless cycle afterward.
+func groupToYAML(name string, groups map[string]InventoryGroupModel, seen, visited map[string]bool) (map[string]interface{}, error) {
+	if seen[name] {
+		return nil, fmt.Errorf("inventory group %q is its own ancestor", name)
+	}
+	seen[name] = true
+	defer delete(seen, name)
+	visited[name] = true
+
+	group, ok := groups[name]
+	if !ok {
+		return nil, fmt.Errorf("inventory group %q is listed as a child but is not defined in inventory", name)
+	}
+
+	out := map[string]interface{}{}
+
+	if !group.Hosts.IsNull() && !group.Hosts.IsUnknown() {
+		hosts := map[string]interface{}{}
+		for host, value := range group.Hosts.Elements() {
+			sv, _ := value.(basetypes.StringValue)
+			vars := map[string]interface{}{}
+			if s := sv.ValueString(); s != "" {
+				if err := json.Unmarshal([]byte(s), &vars); err != nil {
+					return nil, fmt.Errorf("unable to parse host variables for '%s' in group %q: %w", host, name, err)
+				}
+			}
+			hosts[host] = vars
+		}
+		out["hosts"] = hosts
+	}
+
+	if !group.Vars.IsNull() && !group.Vars.IsUnknown() && group.Vars.ValueString() != "" {
+		vars := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(group.Vars.ValueString()), &vars); err != nil {
+			return nil, fmt.Errorf("unable to parse vars for group %q: %w", name, err)
+		}
+		out["vars"] = vars
+	}
+
+	if !group.Children.IsNull() && !group.Children.IsUnknown() {
+		children := map[string]interface{}{}
+		for _, value := range group.Children.Elements() {
+			cv, _ := value.(basetypes.StringValue)
+			child, err := groupToYAML(cv.ValueString(), groups, seen, visited)
+			if err != nil {
+				return nil, err
+			}
+			children[cv.ValueString()] = child
+		}
+		if len(children) > 0 {
+			out["children"] = children
+		}
+	}
+
+	return out, nil
+}
@@ -30,6 +30,8 @@ type AnsiblePlayProvider struct {
 type AnsiblePlayProviderModel struct {
 	AnsiblePlaybookBinary types.String `tfsdk:"ansible_playbook_binary"`
 	Verbosity             types.Int32  `tfsdk:"verbosity"`
+	Vault                 types.Object `tfsdk:"vault"`
+	CheckOnPlan           types.Bool   `tfsdk:"check_on_plan"`
 }
 
 func (p *AnsiblePlayProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -48,6 +50,14 @@ func (p *AnsiblePlayProvider) Schema(ctx context.Context, req provider.SchemaReq
 				MarkdownDescription: "The verbosity level to use when running the playbook.",
 				Optional:            true,
 			},
+			"check_on_plan": schema.BoolAttribute{
+				MarkdownDescription: "Whether resources with `check_mode = true` may run ansible-playbook with " +
+					"`--check --diff` during `terraform plan`. Defaults to false so the side effect is opt-in.",
+				Optional: true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"vault": vaultSchemaBlock(),
 		},
 	}
 }
@@ -83,7 +93,9 @@ func (p *AnsiblePlayProvider) Resources(ctx context.Context) []func() resource.R
 }
 
 func (p *AnsiblePlayProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
-	return []func() ephemeral.EphemeralResource{}
+	return []func() ephemeral.EphemeralResource{
+		NewRunEphemeralResource,
+	}
 }
 
 func (p *AnsiblePlayProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
@@ -0,0 +1,206 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// PlayTaskResultModel describes a single host's outcome for a single task, as surfaced in the
+// `result.plays` computed attribute.
+type PlayTaskResultModel struct {
+	Task   types.String `tfsdk:"task"`
+	Action types.String `tfsdk:"action"`
+	Host   types.String `tfsdk:"host"`
+	Status types.String `tfsdk:"status"`
+	Msg    types.String `tfsdk:"msg"`
+	Stdout types.String `tfsdk:"stdout"`
+	Result types.String `tfsdk:"result"`
+}
+
+var playTaskResultAttrTypes = map[string]attr.Type{
+	"task":   types.StringType,
+	"action": types.StringType,
+	"host":   types.StringType,
+	"status": types.StringType,
+	"msg":    types.StringType,
+	"stdout": types.StringType,
+	"result": types.StringType,
+}
+
+var resultAttrTypes = map[string]attr.Type{
+	"recap": types.MapType{ElemType: types.ObjectType{AttrTypes: hostRecapAttrTypes}},
+	"plays": types.ListType{ElemType: types.ObjectType{AttrTypes: playTaskResultAttrTypes}},
+}
+
+func resultSchemaAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		MarkdownDescription: "The outcome of the most recent run, parsed from ansible-playbook's `json` stdout callback.",
+		Computed:            true,
+		Attributes: map[string]schema.Attribute{
+			"recap": schema.MapNestedAttribute{
+				MarkdownDescription: "The play recap, keyed by hostname.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"ok":          schema.Int64Attribute{Computed: true},
+						"changed":     schema.Int64Attribute{Computed: true},
+						"unreachable": schema.Int64Attribute{Computed: true},
+						"failed":      schema.Int64Attribute{Computed: true},
+						"skipped":     schema.Int64Attribute{Computed: true},
+						"rescued":     schema.Int64Attribute{Computed: true},
+						"ignored":     schema.Int64Attribute{Computed: true},
+					},
+				},
+			},
+			"plays": schema.ListNestedAttribute{
+				MarkdownDescription: "Every task result across every host, with tasks in execution order; the results of one task are listed in no defined host order.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"task":   schema.StringAttribute{Computed: true},
+						"action": schema.StringAttribute{Computed: true},
+						"host":   schema.StringAttribute{Computed: true},
+						"status": schema.StringAttribute{Computed: true},
+						"msg":    schema.StringAttribute{Computed: true},
+						"stdout": schema.StringAttribute{Computed: true},
+						"result": schema.StringAttribute{Computed: true, MarkdownDescription: "The full JSON-encoded task result, for reading register-style values such as set_fact output."},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ansibleJSONCallback mirrors the subset of the ansible.builtin.json stdout callback's document
+// that is surfaced through the `result` computed attribute.
+type ansibleJSONCallback struct {
+	Plays []struct {
+		Tasks []struct {
+			Task struct {
+				Name string `json:"name"`
+			} `json:"task"`
+			Hosts map[string]json.RawMessage `json:"hosts"`
+		} `json:"tasks"`
+	} `json:"plays"`
+	Stats map[string]struct {
+		Ok          int64 `json:"ok"`
+		Changed     int64 `json:"changed"`
+		Unreachable int64 `json:"unreachable"`
+		Failures    int64 `json:"failures"`
+		Skipped     int64 `json:"skipped"`
+		Rescued     int64 `json:"rescued"`
+		Ignored     int64 `json:"ignored"`
+	} `json:"stats"`
+}
+
+type taskHostResult struct {
+	Action      string `json:"action"`
+	Changed     bool   `json:"changed"`
+	Failed      bool   `json:"failed"`
+	Unreachable bool   `json:"unreachable"`
+	Skipped     bool   `json:"skipped"`
+	Msg         string `json:"msg"`
+	Stdout      string `json:"stdout"`
+}
+
+// parseCallbackJSON parses the document produced by ANSIBLE_STDOUT_CALLBACK=json and returns the
+// per-host recap and the flattened list of task results used to populate the `result` attribute.
+func parseCallbackJSON(out []byte) (map[string]hostRecapModel, []PlayTaskResultModel, error) {
+	var doc ansibleJSONCallback
+	if err := json.Unmarshal(out, &doc); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse ansible-playbook json callback output: %w", err)
+	}
+
+	recap := map[string]hostRecapModel{}
+	for host, stats := range doc.Stats {
+		recap[host] = hostRecapModel{
+			Ok:          types.Int64Value(stats.Ok),
+			Changed:     types.Int64Value(stats.Changed),
+			Unreachable: types.Int64Value(stats.Unreachable),
+			Failed:      types.Int64Value(stats.Failures),
+			Skipped:     types.Int64Value(stats.Skipped),
+			Rescued:     types.Int64Value(stats.Rescued),
+			Ignored:     types.Int64Value(stats.Ignored),
+		}
+	}
+
+	var plays []PlayTaskResultModel
+	for _, play := range doc.Plays {
+		for _, task := range play.Tasks {
+			for host, raw := range task.Hosts {
+				var thr taskHostResult
+				if err := json.Unmarshal(raw, &thr); err != nil {
+					return nil, nil, fmt.Errorf("unable to parse task result for host %q: %w", host, err)
+				}
+				plays = append(plays, PlayTaskResultModel{
+					Task:   types.StringValue(task.Task.Name),
+					Action: types.StringValue(thr.Action),
+					Host:   types.StringValue(host),
+					Status: types.StringValue(taskStatus(thr)),
+					Msg:    types.StringValue(thr.Msg),
+					Stdout: types.StringValue(thr.Stdout),
+					Result: types.StringValue(string(raw)),
+				})
+			}
+		}
+	}
+
+	return recap, plays, nil
+}
+
+func taskStatus(thr taskHostResult) string {
+	switch {
+	case thr.Unreachable:
+		return "unreachable"
+	case thr.Failed:
+		return "failed"
+	case thr.Skipped:
+		return "skipped"
+	case thr.Changed:
+		return "changed"
+	default:
+		return "ok"
+	}
+}
+
+// recapText renders a human-readable "PLAY RECAP" section from the parsed per-host recap, since
+// forcing ANSIBLE_STDOUT_CALLBACK=json suppresses ansible-playbook's own human-readable output.
+func recapText(recap map[string]hostRecapModel) string {
+	out := "PLAY RECAP *********************************************************\n"
+	for host, r := range recap {
+		out += fmt.Sprintf("%s : ok=%d changed=%d unreachable=%d failed=%d skipped=%d rescued=%d ignored=%d\n",
+			host, r.Ok.ValueInt64(), r.Changed.ValueInt64(), r.Unreachable.ValueInt64(), r.Failed.ValueInt64(),
+			r.Skipped.ValueInt64(), r.Rescued.ValueInt64(), r.Ignored.ValueInt64())
+	}
+	return out
+}
+
+// resultObjectValue builds the `result` attribute's types.Object from the parsed recap and plays.
+func resultObjectValue(ctx context.Context, recap map[string]hostRecapModel, plays []PlayTaskResultModel) (types.Object, diag.Diagnostics) {
+	recapValue, diags := types.MapValueFrom(ctx, types.ObjectType{AttrTypes: hostRecapAttrTypes}, recap)
+	if diags.HasError() {
+		return types.ObjectNull(resultAttrTypes), diags
+	}
+
+	playsValue, playDiags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: playTaskResultAttrTypes}, plays)
+	diags.Append(playDiags...)
+	if diags.HasError() {
+		return types.ObjectNull(resultAttrTypes), diags
+	}
+
+	obj, objDiags := types.ObjectValue(resultAttrTypes, map[string]attr.Value{
+		"recap": recapValue,
+		"plays": playsValue,
+	})
+	diags.Append(objDiags...)
+	return obj, diags
+}
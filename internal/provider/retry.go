@@ -0,0 +1,126 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// RetryModel describes the resource-level `retry` block, giving ansible-playbook idempotent
+// rollout semantics comparable to a null_resource + local-exec retry loop.
+type RetryModel struct {
+	Attempts        types.Int64 `tfsdk:"attempts"`
+	DelaySeconds    types.Int64 `tfsdk:"delay_seconds"`
+	RetryFailedOnly types.Bool  `tfsdk:"retry_failed_only"`
+}
+
+func retrySchemaBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Retry ansible-playbook on failure, for idempotent rollout semantics without hand-rolling a `null_resource` + `local-exec` loop.",
+		Attributes: map[string]schema.Attribute{
+			"attempts": schema.Int64Attribute{
+				MarkdownDescription: "The total number of times to invoke ansible-playbook, including the first attempt. Defaults to 1 (no retry).",
+				Optional:            true,
+			},
+			"delay_seconds": schema.Int64Attribute{
+				MarkdownDescription: "How long to sleep between attempts.",
+				Optional:            true,
+			},
+			"retry_failed_only": schema.BoolAttribute{
+				MarkdownDescription: "When true, retries are scoped to the hosts ansible-playbook wrote to its `.retry` " +
+					"file via `--limit @<retry_file>`, instead of re-running the whole play against every host. Forces " +
+					"`ANSIBLE_RETRY_FILES_ENABLED=True` for the run, since that has defaulted to false since Ansible 2.8 " +
+					"and no `.retry` file is written without it.",
+				Optional: true,
+			},
+		},
+	}
+}
+
+// parseRetry reads the `retry` block, if any, normalizing a missing/zero `attempts` to 1.
+func parseRetry(ctx context.Context, retry types.Object) (*RetryModel, error) {
+	if retry.IsNull() || retry.IsUnknown() {
+		return nil, nil
+	}
+
+	var r RetryModel
+	if diags := retry.As(ctx, &r, basetypes.ObjectAsOptions{}); diags.HasError() {
+		return nil, fmt.Errorf("unable to read retry configuration: %s", diags[0].Detail())
+	}
+	if r.Attempts.ValueInt64() < 1 {
+		r.Attempts = types.Int64Value(1)
+	}
+	return &r, nil
+}
+
+// retryFilePath returns the path ansible-playbook writes its `.retry` file to on failure: the
+// playbook's own path with its extension replaced by `.retry`.
+func retryFilePath(playbookFile string) string {
+	ext := filepath.Ext(playbookFile)
+	return strings.TrimSuffix(playbookFile, ext) + ".retry"
+}
+
+// retryFailedOnlyRequested reports whether data's retry block asks for retry_failed_only, used by
+// runPlaybook to force ANSIBLE_RETRY_FILES_ENABLED so that retryFilePath's target actually exists.
+func retryFailedOnlyRequested(ctx context.Context, retry types.Object) bool {
+	r, err := parseRetry(ctx, retry)
+	if err != nil || r == nil {
+		return false
+	}
+	return r.RetryFailedOnly.ValueBool()
+}
+
+// executeWithRetry runs the playbook via runPlaybook, honoring the `retry` block by re-invoking on
+// failure, optionally narrowed to the `.retry` file's hosts, until it succeeds or attempts run out.
+func (r *RunResource) executeWithRetry(ctx context.Context, data *RunResourceModel) ([]byte, error) {
+	retry, err := parseRetry(ctx, data.Retry)
+	if err != nil {
+		return nil, err
+	}
+	if retry == nil {
+		return r.runPlaybook(ctx, data)
+	}
+
+	retryFile := retryFilePath(data.PlaybookFile.ValueString())
+	var extraArgs []string
+	var lastErr error
+
+	for attempt := int64(1); attempt <= retry.Attempts.ValueInt64(); attempt++ {
+		out, runErr := r.runPlaybook(ctx, data, extraArgs...)
+		if runErr == nil {
+			_ = os.Remove(retryFile)
+			return out, nil
+		}
+		lastErr = runErr
+
+		if attempt == retry.Attempts.ValueInt64() {
+			break
+		}
+
+		if retry.DelaySeconds.ValueInt64() > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(retry.DelaySeconds.ValueInt64()) * time.Second):
+			}
+		}
+
+		if retry.RetryFailedOnly.ValueBool() {
+			if _, statErr := os.Stat(retryFile); statErr == nil {
+				extraArgs = []string{"--limit", "@" + retryFile}
+			}
+		}
+	}
+
+	return nil, lastErr
+}
@@ -11,6 +11,7 @@ import (
 	"math/rand/v2"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -36,10 +37,26 @@ type RunResource struct {
 }
 
 type RunResourceModel struct {
-	Id           types.Int64  `tfsdk:"id"`
-	Hosts        types.List   `tfsdk:"hosts"`
-	PlaybookFile types.String `tfsdk:"playbook_file"`
-	ExtraVars    types.String `tfsdk:"extra_vars_json"`
+	Id                 types.Int64  `tfsdk:"id"`
+	Hosts              types.List   `tfsdk:"hosts"`
+	Inventory          types.Map    `tfsdk:"inventory"`
+	PlaybookFile       types.String `tfsdk:"playbook_file"`
+	ExtraVars          types.String `tfsdk:"extra_vars_json"`
+	ExtraVarsEncrypted types.String `tfsdk:"extra_vars_encrypted"`
+	VaultID            types.String `tfsdk:"vault_id"`
+	Connection         types.Object `tfsdk:"connection"`
+	CheckMode          types.Bool   `tfsdk:"check_mode"`
+	PendingChanges     types.Int64  `tfsdk:"pending_changes"`
+	Diff               types.String `tfsdk:"diff"`
+	Tags               types.List   `tfsdk:"tags"`
+	SkipTags           types.List   `tfsdk:"skip_tags"`
+	Limit              types.String `tfsdk:"limit"`
+	Forks              types.Int64  `tfsdk:"forks"`
+	StartAtTask        types.String `tfsdk:"start_at_task"`
+	FlushCache         types.Bool   `tfsdk:"flush_cache"`
+	Retry              types.Object `tfsdk:"retry"`
+	Stdout             types.String `tfsdk:"stdout"`
+	Result             types.Object `tfsdk:"result"`
 }
 
 func (r *RunResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -52,8 +69,10 @@ func (r *RunResource) Schema(ctx context.Context, req resource.SchemaRequest, re
 playbook_file on the set of hosts with any extra_vars provided as json.
 
 Note, this resource will not automatically re-run if the playbook file has changed. And may not run if there have been
-no changes to the hosts or vars either. To ensure the run is always executed, use the ` + "`" + `lifecycle.replace_triggered_by` + "`" + `
-attribute to re-execute the run based on the hash of the playbook file or timestamp.
+no changes to the hosts or vars either. Set ` + "`" + `check_mode` + "`" + ` on the resource along with the provider's
+` + "`" + `check_on_plan` + "`" + ` to have ` + "`" + `terraform plan` + "`" + ` detect drift with ` + "`" + `ansible-playbook --check` + "`" + `
+and automatically trigger a re-run, or use the ` + "`" + `lifecycle.replace_triggered_by` + "`" + ` attribute to force a re-run
+based on the hash of the playbook file or timestamp.
 `,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.Int64Attribute{
@@ -65,9 +84,10 @@ attribute to re-execute the run based on the hash of the playbook file or timest
 			},
 			"hosts": schema.ListAttribute{
 				ElementType:         types.StringType,
-				MarkdownDescription: "A list of hosts to run the playbook on. Each host (an ip or hostname) may be followed by a space and a JSON object of host attributes.",
-				Required:            true,
+				MarkdownDescription: "A list of hosts to run the playbook on. Each host (an ip or hostname) may be followed by a space and a JSON object of host attributes. Merged into the implicit `all` group alongside any groups declared in `inventory`.",
+				Optional:            true,
 			},
+			"inventory": inventorySchemaAttribute(),
 			"playbook_file": schema.StringAttribute{
 				MarkdownDescription: "A path to the playbook file to run.",
 				Required:            true,
@@ -76,6 +96,68 @@ attribute to re-execute the run based on the hash of the playbook file or timest
 				MarkdownDescription: "A json-encoded map of extra variables to pass to the playbook.",
 				Optional:            true,
 			},
+			"extra_vars_encrypted": schema.StringAttribute{
+				MarkdownDescription: "A vault-encrypted YAML or JSON blob of extra variables. Written to a temporary " +
+					"file and passed via `--extra-vars @<file>`, so secrets round-tripped through state remain encrypted.",
+				Optional:  true,
+				Sensitive: true,
+			},
+			"vault_id": schema.StringAttribute{
+				MarkdownDescription: "A vault-id label used to select among multiple vault identities, passed as `--vault-id <label>@<source>`.",
+				Optional:            true,
+			},
+			"check_mode": schema.BoolAttribute{
+				MarkdownDescription: "When true, and the provider's `check_on_plan` is also true, `terraform plan` runs " +
+					"ansible-playbook with `--check --diff -D` and, if it reports any changed task, marks `pending_changes` " +
+					"and `diff` unknown so that an update is planned even if `hosts`, `extra_vars_json`, and `playbook_file` " +
+					"are unchanged.",
+				Optional: true,
+			},
+			"pending_changes": schema.Int64Attribute{
+				MarkdownDescription: "Set to 0 after every run. When `check_mode` finds drift during `terraform plan`, it is " +
+					"planned as unknown to force the update; the check-mode changed count itself is not stored.",
+				Computed:            true,
+			},
+			"diff": schema.StringAttribute{
+				MarkdownDescription: "Set to an empty string after every run. When `check_mode` finds drift during `terraform plan`, " +
+					"it is planned as unknown to force the update; the check-mode diff itself is not stored.",
+				Computed:            true,
+			},
+			"stdout": schema.StringAttribute{
+				MarkdownDescription: "The human-readable play recap for the most recent run.",
+				Computed:            true,
+			},
+			"tags": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Only run plays and tasks tagged with these values, passed as `--tags`.",
+				Optional:            true,
+			},
+			"skip_tags": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Skip plays and tasks tagged with these values, passed as `--skip-tags`.",
+				Optional:            true,
+			},
+			"limit": schema.StringAttribute{
+				MarkdownDescription: "Further limit the inventory to hosts matching this pattern, passed as `--limit`.",
+				Optional:            true,
+			},
+			"forks": schema.Int64Attribute{
+				MarkdownDescription: "The number of hosts ansible-playbook should run in parallel, passed as `--forks`.",
+				Optional:            true,
+			},
+			"start_at_task": schema.StringAttribute{
+				MarkdownDescription: "Start the playbook at the named task, passed as `--start-at-task`.",
+				Optional:            true,
+			},
+			"flush_cache": schema.BoolAttribute{
+				MarkdownDescription: "Clear the fact cache for every host before running, passed as `--flush-cache`.",
+				Optional:            true,
+			},
+			"result": resultSchemaAttribute(),
+		},
+		Blocks: map[string]schema.Block{
+			"connection": connectionSchemaBlock(),
+			"retry":      retrySchemaBlock(),
 		},
 	}
 }
@@ -92,33 +174,39 @@ func (r *RunResource) Configure(ctx context.Context, req resource.ConfigureReque
 	}
 }
 
-func (r *RunResource) execute(ctx context.Context, data RunResourceModel) error {
-	hosts := make(map[string]interface{})
-	for _, value := range data.Hosts.Elements() {
-		hv, _ := value.(basetypes.StringValue)
-		hostAndJsonAttr := strings.SplitN(hv.ValueString(), " ", 2)
-		attr := map[string]interface{}{}
-		if len(hostAndJsonAttr) == 2 {
-			if err := json.Unmarshal([]byte(hostAndJsonAttr[1]), &attr); err != nil {
-				return fmt.Errorf("unable to parse host attributes for '%s': %w", hostAndJsonAttr[0], err)
-			}
+// ansibleStdoutCallbackEnv forces the json stdout callback regardless of what the user's own
+// environment sets, so that execute can reliably parse ansible-playbook's output.
+const ansibleStdoutCallbackEnv = "ANSIBLE_STDOUT_CALLBACK=json"
+
+// stringListCSV joins a types.List of strings into the comma-separated form ansible-playbook
+// expects for flags like --tags and --skip-tags.
+func stringListCSV(l types.List) string {
+	var parts []string
+	for _, v := range l.Elements() {
+		if sv, ok := v.(basetypes.StringValue); ok {
+			parts = append(parts, sv.ValueString())
 		}
-		hosts[hostAndJsonAttr[0]] = attr
+	}
+	return strings.Join(parts, ",")
+}
+
+// runPlaybook builds the temporary inventory file and ansible-playbook invocation shared by a real
+// run and a plan-time --check run, returning the json-callback stdout.
+func (r *RunResource) runPlaybook(ctx context.Context, data *RunResourceModel, extraArgs ...string) ([]byte, error) {
+	inventory, err := buildInventory(ctx, data.Hosts, data.Inventory)
+	if err != nil {
+		return nil, err
 	}
 
 	tf, err := os.CreateTemp(os.TempDir(), "inventory-*.yml")
 	if err != nil {
-		return fmt.Errorf("failed to create temporary inventory file: %w", err)
+		return nil, fmt.Errorf("failed to create temporary inventory file: %w", err)
 	}
-	if err := yaml.NewEncoder(tf).Encode(map[string]interface{}{
-		"all": map[string]interface{}{
-			"hosts": hosts,
-		},
-	}); err != nil {
-		return fmt.Errorf("failed to write temporary inventory file: %w", err)
+	if err := yaml.NewEncoder(tf).Encode(inventory); err != nil {
+		return nil, fmt.Errorf("failed to write temporary inventory file: %w", err)
 	}
 	if err := tf.Close(); err != nil {
-		return fmt.Errorf("failed to close temporary inventory file: %w", err)
+		return nil, fmt.Errorf("failed to close temporary inventory file: %w", err)
 	}
 	args := []string{
 		data.PlaybookFile.ValueString(), "-i", tf.Name(),
@@ -128,14 +216,57 @@ func (r *RunResource) execute(ctx context.Context, data RunResourceModel) error
 		args = append(args, "--extra-vars", data.ExtraVars.ValueString())
 	}
 
-	if v := r.providerModel.Verbosity.ValueInt32(); v > 0 {
-		args = append(args, "-"+strings.Repeat("v", int(v)))
+	if !data.ExtraVarsEncrypted.IsNull() && data.ExtraVarsEncrypted.ValueString() != "" {
+		f, err := writeTempFile("extra-vars-encrypted-*", []byte(data.ExtraVarsEncrypted.ValueString()), 0o600)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(f)
+		args = append(args, "--extra-vars", "@"+f)
+	}
+
+	vargs, vcleanup, err := vaultArgs(ctx, r.providerModel, data.VaultID)
+	if err != nil {
+		return nil, err
+	}
+	defer vcleanup()
+	args = append(args, vargs...)
+
+	cargs, cenv, ccleanup, err := connectionArgs(ctx, data.Connection)
+	if err != nil {
+		return nil, err
+	}
+	defer ccleanup()
+	args = append(args, cargs...)
+
+	if !data.Tags.IsNull() && len(data.Tags.Elements()) > 0 {
+		args = append(args, "--tags", stringListCSV(data.Tags))
+	}
+	if !data.SkipTags.IsNull() && len(data.SkipTags.Elements()) > 0 {
+		args = append(args, "--skip-tags", stringListCSV(data.SkipTags))
+	}
+	if !data.Limit.IsNull() && data.Limit.ValueString() != "" {
+		args = append(args, "--limit", data.Limit.ValueString())
+	}
+	if !data.Forks.IsNull() && data.Forks.ValueInt64() > 0 {
+		args = append(args, "--forks", strconv.FormatInt(data.Forks.ValueInt64(), 10))
+	}
+	if !data.StartAtTask.IsNull() && data.StartAtTask.ValueString() != "" {
+		args = append(args, "--start-at-task", data.StartAtTask.ValueString())
 	}
-	binary := r.providerModel.AnsiblePlaybookBinary.ValueString()
-	if binary == "" {
-		binary = "ansible-playbook"
+	if data.FlushCache.ValueBool() {
+		args = append(args, "--flush-cache")
+	}
+
+	args = append(args, extraArgs...)
+	args = append(args, verbosityArgs(r.providerModel)...)
+	c := exec.CommandContext(ctx, resolveBinary(r.providerModel), args...)
+	c.Env = append(append(os.Environ(), ansibleStdoutCallbackEnv), cenv...)
+	if retryFailedOnlyRequested(ctx, data.Retry) {
+		// retry_failed_only reads the .retry file retryFilePath expects, but retry_files_enabled
+		// has defaulted to false since Ansible 2.8; force it so the file is actually written.
+		c.Env = append(c.Env, "ANSIBLE_RETRY_FILES_ENABLED=True")
 	}
-	c := exec.CommandContext(ctx, binary, args...)
 	outBuffer := &bytes.Buffer{}
 	errBuffer := &bytes.Buffer{}
 
@@ -146,16 +277,44 @@ func (r *RunResource) execute(ctx context.Context, data RunResourceModel) error
 	tflog.Info(ctx, "ansible play output: "+outBuffer.String())
 
 	if err != nil {
-		return fmt.Errorf("ansible play failed: %w: %s", err, errBuffer.String())
+		return nil, fmt.Errorf("ansible play failed: %w: %s", err, errBuffer.String())
+	}
+
+	return outBuffer.Bytes(), nil
+}
+
+func (r *RunResource) execute(ctx context.Context, data *RunResourceModel) error {
+	out, err := r.executeWithRetry(ctx, data)
+	if err != nil {
+		return err
 	}
 
+	recap, plays, err := parseCallbackJSON(out)
+	if err != nil {
+		return err
+	}
+
+	result, diags := resultObjectValue(ctx, recap, plays)
+	if diags.HasError() {
+		return fmt.Errorf("unable to build result attribute: %s", diags[0].Detail())
+	}
+
+	data.Stdout = types.StringValue(recapText(recap))
+	data.Result = result
+
+	// ModifyPlan may have left pending_changes/diff unknown to force this Create/Update without
+	// promising a specific value; now that the run actually happened, settle them at the real
+	// post-run values so the next plan (with no further drift) is clean.
+	data.PendingChanges = types.Int64Value(0)
+	data.Diff = types.StringValue("")
+
 	return nil
 }
 
 func (r *RunResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data RunResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
-	if err := r.execute(ctx, data); err != nil {
+	if err := r.execute(ctx, &data); err != nil {
 		resp.Diagnostics.AddError("Error", err.Error())
 	}
 	if resp.Diagnostics.HasError() {
@@ -187,7 +346,7 @@ func (r *RunResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	var data RunResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 
-	if err := r.execute(ctx, data); err != nil {
+	if err := r.execute(ctx, &data); err != nil {
 		resp.Diagnostics.AddError("Error", err.Error())
 	}
 
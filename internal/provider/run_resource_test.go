@@ -5,13 +5,17 @@ package provider
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"os"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
 	"github.com/hashicorp/terraform-plugin-testing/statecheck"
@@ -91,3 +95,131 @@ func testAccExampleResourceConfig(hosts []string, playbook string) string {
 	_, _ = f.WriteTo(buf)
 	return buf.String()
 }
+
+var inventoryGroupAttrTypes = map[string]attr.Type{
+	"hosts":    types.MapType{ElemType: types.StringType},
+	"vars":     types.StringType,
+	"children": types.ListType{ElemType: types.StringType},
+}
+
+func inventoryGroupValue(t *testing.T, children ...string) attr.Value {
+	t.Helper()
+
+	childValues := make([]attr.Value, len(children))
+	for i, c := range children {
+		childValues[i] = types.StringValue(c)
+	}
+
+	return types.ObjectValueMust(inventoryGroupAttrTypes, map[string]attr.Value{
+		"hosts":    types.MapNull(types.StringType),
+		"vars":     types.StringNull(),
+		"children": types.ListValueMust(types.StringType, childValues),
+	})
+}
+
+// TestBuildInventoryDiamond covers a group reachable as a legitimate child of two different
+// parents (a diamond, not a cycle): datacenter -> {production, us_east} -> webservers.
+func TestBuildInventoryDiamond(t *testing.T) {
+	inventory := types.MapValueMust(types.ObjectType{AttrTypes: inventoryGroupAttrTypes}, map[string]attr.Value{
+		"datacenter": inventoryGroupValue(t, "production", "us_east"),
+		"production": inventoryGroupValue(t, "webservers"),
+		"us_east":    inventoryGroupValue(t, "webservers"),
+		"webservers": inventoryGroupValue(t),
+	})
+
+	result, err := buildInventory(context.Background(), types.ListNull(types.StringType), inventory)
+	require.NoError(t, err)
+
+	all := result["all"].(map[string]interface{})
+	children := all["children"].(map[string]interface{})
+	_, ok := children["datacenter"]
+	require.True(t, ok, "expected datacenter to be nested under all.children")
+	_, ok = children["production"]
+	require.False(t, ok, "production is nested under datacenter, not a top-level child")
+}
+
+// TestGroupToYAMLCycle covers a true cycle, which must still be rejected.
+func TestGroupToYAMLCycle(t *testing.T) {
+	groups := map[string]InventoryGroupModel{
+		"a": {Children: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("b")})},
+		"b": {Children: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("a")})},
+	}
+
+	_, err := groupToYAML("a", groups, map[string]bool{}, map[string]bool{})
+	require.ErrorContains(t, err, "its own ancestor")
+}
+
+// TestBuildInventoryRootlessCycle covers a cycle with no root: every group is listed as some
+// other group's child, so the top-level loop would otherwise skip all of them without ever
+// calling groupToYAML, silently dropping the whole cycle instead of rejecting it.
+func TestBuildInventoryRootlessCycle(t *testing.T) {
+	inventory := types.MapValueMust(types.ObjectType{AttrTypes: inventoryGroupAttrTypes}, map[string]attr.Value{
+		"a": inventoryGroupValue(t, "b"),
+		"b": inventoryGroupValue(t, "a"),
+	})
+
+	_, err := buildInventory(context.Background(), types.ListNull(types.StringType), inventory)
+	require.ErrorContains(t, err, "unreachable")
+}
+
+func TestParseCallbackJSON(t *testing.T) {
+	out := []byte(`{
+		"plays": [{
+			"tasks": [{
+				"task": {"name": "install package"},
+				"hosts": {
+					"web1": {"action": "package", "changed": true, "msg": "installed"}
+				}
+			}]
+		}],
+		"stats": {
+			"web1": {"ok": 2, "changed": 1, "unreachable": 0, "failures": 0, "skipped": 0, "rescued": 0, "ignored": 0}
+		}
+	}`)
+
+	recap, plays, err := parseCallbackJSON(out)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), recap["web1"].Changed.ValueInt64())
+	require.Len(t, plays, 1)
+	require.Equal(t, "changed", plays[0].Status.ValueString())
+	require.Equal(t, "install package", plays[0].Task.ValueString())
+}
+
+func TestParseCheckMode(t *testing.T) {
+	out := []byte(`{
+		"plays": [{
+			"tasks": [{
+				"task": {"name": "write config"},
+				"hosts": {
+					"web1": {
+						"changed": true,
+						"diff": {"before": "old\n", "after": "new\n"}
+					}
+				}
+			}]
+		}],
+		"stats": {
+			"web1": {"changed": 1}
+		}
+	}`)
+
+	pending, diff, err := parseCheckMode(out)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), pending)
+	require.Contains(t, diff, "web1 / write config")
+	require.Contains(t, diff, "-old\n")
+	require.Contains(t, diff, "+new\n")
+	require.False(t, strings.Contains(diff, `"before"`), "diff should be rendered as text, not raw JSON")
+}
+
+func TestRetryFilePath(t *testing.T) {
+	cases := map[string]string{
+		"site.yml":        "site.retry",
+		"site.yaml":       "site.retry",
+		"playbooks/a.yml": "playbooks/a.retry",
+		"noext":           "noext.retry",
+	}
+	for in, want := range cases {
+		require.Equal(t, want, retryFilePath(in))
+	}
+}
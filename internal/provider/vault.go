@@ -0,0 +1,128 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	providerschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// VaultModel describes the provider-level `vault` block used to decrypt vaulted playbooks and the
+// `extra_vars_encrypted` resource attribute via ansible-vault.
+type VaultModel struct {
+	Password        types.String `tfsdk:"password"`
+	PasswordFile    types.String `tfsdk:"password_file"`
+	PasswordCommand types.String `tfsdk:"password_command"`
+}
+
+func vaultSchemaBlock() providerschema.SingleNestedBlock {
+	return providerschema.SingleNestedBlock{
+		MarkdownDescription: "Ansible Vault configuration, used to decrypt vaulted playbooks and the " +
+			"`extra_vars_encrypted` resource attribute. At most one of `password`, `password_file`, or " +
+			"`password_command` may be set.",
+		Attributes: map[string]providerschema.Attribute{
+			"password": providerschema.StringAttribute{
+				MarkdownDescription: "The vault password. Materialized to a mode-0600 temporary file for the duration of each run.",
+				Optional:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(
+						path.MatchRoot("vault").AtName("password_file"),
+						path.MatchRoot("vault").AtName("password_command"),
+					),
+				},
+			},
+			"password_file": providerschema.StringAttribute{
+				MarkdownDescription: "Path to a file containing the vault password, passed to ansible-playbook as-is.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(
+						path.MatchRoot("vault").AtName("password"),
+						path.MatchRoot("vault").AtName("password_command"),
+					),
+				},
+			},
+			"password_command": providerschema.StringAttribute{
+				MarkdownDescription: "A shell command whose stdout is the vault password. Wrapped in an executable " +
+					"temporary script, the form ansible-vault expects of a vault password script.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(
+						path.MatchRoot("vault").AtName("password"),
+						path.MatchRoot("vault").AtName("password_file"),
+					),
+				},
+			},
+		},
+	}
+}
+
+// vaultArgs resolves the provider's `vault` block (if any) into the ansible-playbook flags used to
+// supply the vault password, along with a cleanup func that removes any temporary file it created.
+// vaultID, when set, selects `--vault-id <label>@<source>` over the plain `--vault-password-file`.
+func vaultArgs(ctx context.Context, providerModel AnsiblePlayProviderModel, vaultID types.String) ([]string, func(), error) {
+	noop := func() {}
+	if providerModel.Vault.IsNull() || providerModel.Vault.IsUnknown() {
+		return nil, noop, nil
+	}
+
+	var vault VaultModel
+	if diags := providerModel.Vault.As(ctx, &vault, basetypes.ObjectAsOptions{}); diags.HasError() {
+		return nil, noop, fmt.Errorf("unable to read vault configuration: %s", diags[0].Detail())
+	}
+
+	var source string
+	cleanup := noop
+	switch {
+	case !vault.Password.IsNull() && vault.Password.ValueString() != "":
+		f, err := writeTempFile("vault-password-*", []byte(vault.Password.ValueString()), 0o600)
+		if err != nil {
+			return nil, noop, err
+		}
+		source = f
+		cleanup = func() { _ = os.Remove(f) }
+	case !vault.PasswordCommand.IsNull() && vault.PasswordCommand.ValueString() != "":
+		script := "#!/bin/sh\nexec " + vault.PasswordCommand.ValueString() + "\n"
+		f, err := writeTempFile("vault-password-command-*", []byte(script), 0o700)
+		if err != nil {
+			return nil, noop, err
+		}
+		source = f
+		cleanup = func() { _ = os.Remove(f) }
+	case !vault.PasswordFile.IsNull() && vault.PasswordFile.ValueString() != "":
+		source = vault.PasswordFile.ValueString()
+	default:
+		return nil, noop, nil
+	}
+
+	if !vaultID.IsNull() && vaultID.ValueString() != "" {
+		return []string{"--vault-id", vaultID.ValueString() + "@" + source}, cleanup, nil
+	}
+	return []string{"--vault-password-file", source}, cleanup, nil
+}
+
+// writeTempFile writes content to a new temporary file with the given mode and returns its path.
+func writeTempFile(pattern string, content []byte, mode os.FileMode) (string, error) {
+	f, err := os.CreateTemp(os.TempDir(), pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(content); err != nil {
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := f.Chmod(mode); err != nil {
+		return "", fmt.Errorf("failed to set temporary file permissions: %w", err)
+	}
+	return f.Name(), nil
+}